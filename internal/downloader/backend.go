@@ -0,0 +1,25 @@
+package downloader
+
+import (
+	"context"
+	"fmt"
+)
+
+// Backend drives the retrieval and transcoding of a single track. Download
+// runs synchronously and reports progress on progressChan until the track
+// reaches StatusCompleted or StatusFailed.
+type Backend interface {
+	Download(ctx context.Context, id int, url string, config Config, progressChan chan<- ProgressMsg)
+}
+
+// NewBackend resolves a backend by name for the -backend CLI flag.
+func NewBackend(name string) (Backend, error) {
+	switch name {
+	case "", "ytdlp":
+		return YTDLPBackend{}, nil
+	case "native":
+		return NativeBackend{}, nil
+	default:
+		return nil, fmt.Errorf("unknown backend %q (want ytdlp or native)", name)
+	}
+}
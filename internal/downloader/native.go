@@ -0,0 +1,255 @@
+package downloader
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kkdai/youtube/v2"
+)
+
+// NativeBackend drives downloads directly via the YouTube player API
+// (github.com/kkdai/youtube/v2) and pipes the raw audio stream into ffmpeg
+// for transcoding, avoiding a yt-dlp dependency entirely.
+type NativeBackend struct{}
+
+func (b NativeBackend) Download(ctx context.Context, id int, url string, config Config, progressChan chan<- ProgressMsg) {
+	progressChan <- ProgressMsg{ID: id, Status: StatusDownloading, Progress: 0}
+
+	client := youtube.Client{}
+
+	video, err := client.GetVideoContext(ctx, url)
+	if err != nil {
+		progressChan <- ProgressMsg{ID: id, Status: StatusFailed, Error: fmt.Errorf("fetch video info: %w", err)}
+		return
+	}
+
+	title := video.Title
+	progressChan <- ProgressMsg{ID: id, Status: StatusDownloading, Title: title}
+
+	format := bestAudioFormat(video.Formats.WithAudioChannels())
+	if format == nil {
+		progressChan <- ProgressMsg{ID: id, Status: StatusFailed, Title: title, Error: fmt.Errorf("no audio-only stream available")}
+		return
+	}
+
+	var lastErr error
+	var finalPath string
+	var finalSize int64
+	for attempt := 0; attempt <= config.MaxRetries; attempt++ {
+		if attempt > 0 {
+			progressChan <- ProgressMsg{ID: id, Status: StatusRetrying, Title: title, Attempt: attempt}
+			select {
+			case <-ctx.Done():
+				progressChan <- ProgressMsg{ID: id, Status: StatusFailed, Title: title, Error: ctx.Err()}
+				return
+			case <-time.After(retryDelay(config.RetryBackoff, attempt)):
+			}
+		}
+
+		if config.Resume {
+			finalPath, finalSize, lastErr = b.downloadResumable(ctx, id, url, video, format, title, config, progressChan)
+		} else {
+			finalPath, finalSize, lastErr = b.downloadDirect(ctx, id, video, format, title, config, progressChan)
+		}
+		if lastErr == nil {
+			break
+		}
+	}
+
+	if lastErr != nil {
+		progressChan <- ProgressMsg{ID: id, Status: StatusFailed, Title: title, Error: lastErr}
+		return
+	}
+
+	// The download itself already succeeded; peaks generation is an
+	// independent post-processing step and must not re-run it on failure.
+	//
+	// A local file only exists to analyze when downloadResumable staged
+	// one, or downloadDirect's tee landed on local disk (Sink == "local");
+	// a direct stream to a remote sink never touches disk, so there is
+	// nothing for ffmpeg to re-read.
+	stagedLocally := config.Resume || config.Sink == "" || config.Sink == "local"
+	if config.Peaks && stagedLocally {
+		progressChan <- ProgressMsg{ID: id, Status: StatusAnalyzing, Title: title}
+		outfile := outputPath(config.OutputDir, title, config.Format)
+		if perr := generatePeaks(title, outfile, config); perr != nil {
+			progressChan <- ProgressMsg{ID: id, Status: StatusFailed, Title: title, Error: fmt.Errorf("generate peaks: %w", perr)}
+			return
+		}
+	}
+	progressChan <- ProgressMsg{ID: id, Status: StatusCompleted, Progress: 100, Title: title, OutputPath: finalPath, Bytes: finalSize}
+}
+
+// downloadDirect streams the audio straight into ffmpeg's stdin and tees
+// ffmpeg's transcoded stdout straight into config.Uploader, so the full
+// output file is never staged on local disk. It cannot be resumed
+// mid-transfer. Returns the Uploader-reported location and size of the
+// finished track.
+func (NativeBackend) downloadDirect(ctx context.Context, id int, video *youtube.Video, format *youtube.Format, title string, config Config, progressChan chan<- ProgressMsg) (string, int64, error) {
+	client := youtube.Client{}
+
+	stream, size, err := client.GetStreamContext(ctx, video, format)
+	if err != nil {
+		return "", 0, fmt.Errorf("open stream: %w", err)
+	}
+	defer stream.Close()
+
+	codec := ffmpegCodec(config.Format)
+	key := filepath.Base(outputPath(config.OutputDir, title, config.Format))
+
+	cmd := exec.CommandContext(ctx, "ffmpeg",
+		"-y",
+		"-i", "pipe:0",
+		"-vn",
+		"-c:a", codec,
+		"-b:a", config.Quality+"k",
+		"-f", config.Format,
+		"pipe:1",
+	)
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return "", 0, err
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return "", 0, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return "", 0, err
+	}
+
+	downloadDone := make(chan error, 1)
+	go func() {
+		reader := &countingReader{r: stream, total: size, onProgress: func(pct float64) {
+			progressChan <- ProgressMsg{ID: id, Status: StatusDownloading, Progress: pct, Title: title}
+		}}
+		_, copyErr := io.Copy(stdin, reader)
+		stdin.Close()
+		downloadDone <- copyErr
+	}()
+
+	progressChan <- ProgressMsg{ID: id, Status: StatusConverting, Title: title}
+	progressChan <- ProgressMsg{ID: id, Status: StatusUploading, Title: title}
+
+	// total is deliberately left zero: size is the byte length of the
+	// source stream, not of ffmpeg's transcoded stdout, and the two can
+	// differ significantly (e.g. opus re-encoded to wav). countingReader
+	// only reports a percentage when total is known, so this just stops
+	// claiming an upload progress we can't actually measure.
+	uploadReader := &countingReader{r: stdout, onProgress: func(pct float64) {
+		progressChan <- ProgressMsg{ID: id, Status: StatusUploading, Progress: pct, Title: title}
+	}}
+	location, uploadErr := config.Uploader.Upload(ctx, key, uploadReader, 0)
+
+	copyErr := <-downloadDone
+	waitErr := cmd.Wait()
+
+	if copyErr != nil {
+		return "", 0, fmt.Errorf("stream to ffmpeg: %w", copyErr)
+	}
+	if waitErr != nil {
+		return "", 0, fmt.Errorf("ffmpeg: %w", waitErr)
+	}
+	if uploadErr != nil {
+		return "", 0, fmt.Errorf("upload: %w", uploadErr)
+	}
+
+	return location, uploadReader.read, nil
+}
+
+// audioCodecRank orders audio codecs by preference, lowest index wins.
+var audioCodecRank = []string{"opus", "mp4a", "vorbis"}
+
+// bestAudioFormat picks the highest-bitrate stream among the most-preferred
+// codec present, ranking opus > m4a > webm/vorbis.
+func bestAudioFormat(formats youtube.FormatList) *youtube.Format {
+	if len(formats) == 0 {
+		return nil
+	}
+
+	rank := func(f youtube.Format) int {
+		for i, codec := range audioCodecRank {
+			if strings.Contains(f.MimeType, codec) {
+				return i
+			}
+		}
+		return len(audioCodecRank)
+	}
+
+	sorted := make(youtube.FormatList, len(formats))
+	copy(sorted, formats)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ri, rj := rank(sorted[i]), rank(sorted[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return sorted[i].Bitrate > sorted[j].Bitrate
+	})
+
+	return &sorted[0]
+}
+
+// ffmpegCodec maps a gofetch-audio output format to the ffmpeg encoder used
+// to produce it.
+func ffmpegCodec(format string) string {
+	switch format {
+	case "mp3":
+		return "libmp3lame"
+	case "opus":
+		return "libopus"
+	case "wav":
+		return "pcm_s16le"
+	default: // m4a and anything AAC-based
+		return "aac"
+	}
+}
+
+func outputPath(dir, title, format string) string {
+	safe := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		case r == ' ':
+			return '_'
+		default:
+			return -1
+		}
+	}, title)
+	if safe == "" {
+		safe = "track"
+	}
+	return dir + "/" + safe + "." + format
+}
+
+// countingReader wraps an io.Reader and reports read progress as a
+// percentage of total (when known) via onProgress.
+type countingReader struct {
+	r          io.Reader
+	total      int64
+	read       int64
+	onProgress func(pct float64)
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	if n > 0 {
+		c.read += int64(n)
+		if c.total > 0 && c.onProgress != nil {
+			pct := float64(c.read) / float64(c.total) * 100
+			if pct > 100 {
+				pct = 100
+			}
+			c.onProgress(pct)
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,29 @@
+package downloader
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ddbaque/gofetch-audio/internal/peaks"
+)
+
+// generatePeaks computes a waveform peaks sidecar for outPath and writes it
+// to "<name>.peaks.json" alongside it.
+func generatePeaks(title, outPath string, config Config) error {
+	if outPath == "" {
+		return fmt.Errorf("compute peaks: unknown output path")
+	}
+
+	p, err := peaks.Compute(outPath, config.PeaksBins)
+	if err != nil {
+		return fmt.Errorf("compute peaks: %w", err)
+	}
+	p.Title = title
+
+	sidecar := strings.TrimSuffix(outPath, "."+config.Format) + ".peaks.json"
+	if err := peaks.WriteSidecar(sidecar, p); err != nil {
+		return fmt.Errorf("write peaks sidecar: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,169 @@
+package downloader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+
+	"github.com/kkdai/youtube/v2"
+)
+
+// downloadResumable stages the raw audio stream in a `<title>.<format>.part` file
+// using HTTP Range requests, so a killed process can pick back up from the
+// bytes already on disk instead of restarting the transfer. Once the part
+// file is complete it is handed to ffmpeg for transcoding. Returns the
+// Uploader-reported location (or local path) and size of the finished
+// track.
+func (NativeBackend) downloadResumable(ctx context.Context, id int, url string, video *youtube.Video, format *youtube.Format, title string, config Config, progressChan chan<- ProgressMsg) (string, int64, error) {
+	state := config.StateStore
+	if state == nil {
+		return "", 0, fmt.Errorf("resume requested but no state store configured")
+	}
+
+	partPath := outputPath(config.OutputDir, title, config.Format) + ".part"
+	if cp, ok := state.Get(url); ok && cp.PartPath != "" {
+		partPath = cp.PartPath
+	}
+
+	client := youtube.Client{}
+	streamURL, err := client.GetStreamURLContext(ctx, video, format)
+	if err != nil {
+		return "", 0, fmt.Errorf("resolve stream url: %w", err)
+	}
+
+	var offset int64
+	if fi, statErr := os.Stat(partPath); statErr == nil {
+		offset = fi.Size()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, streamURL, nil)
+	if err != nil {
+		return "", 0, err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", 0, fmt.Errorf("fetch stream: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		return "", 0, fmt.Errorf("fetch stream: unexpected status %s", resp.Status)
+	}
+	// Server ignored our Range header; restart the part file from scratch.
+	if offset > 0 && resp.StatusCode == http.StatusOK {
+		offset = 0
+	}
+
+	total := offset + resp.ContentLength
+
+	flags := os.O_CREATE | os.O_WRONLY
+	if offset > 0 {
+		flags |= os.O_APPEND
+	} else {
+		flags |= os.O_TRUNC
+	}
+	partFile, err := os.OpenFile(partPath, flags, 0644)
+	if err != nil {
+		return "", 0, fmt.Errorf("open part file: %w", err)
+	}
+
+	written := offset
+	reader := &countingReader{r: resp.Body, total: total, onProgress: func(pct float64) {
+		progressChan <- ProgressMsg{ID: id, Status: StatusDownloading, Progress: pct, Title: title}
+	}}
+	reader.read = offset
+
+	buf := make([]byte, 32*1024)
+	copyErr := copyWithCheckpoint(partFile, reader, buf, func(n int64) {
+		written += n
+		_ = state.Set(url, Checkpoint{BytesWritten: written, PartPath: partPath})
+	})
+	partFile.Close()
+	if copyErr != nil {
+		return "", 0, fmt.Errorf("stage part file: %w", copyErr)
+	}
+
+	progressChan <- ProgressMsg{ID: id, Status: StatusConverting, Progress: 100, Title: title}
+
+	if err := transcodeFile(ctx, partPath, config); err != nil {
+		return "", 0, err
+	}
+
+	os.Remove(partPath)
+	_ = state.Clear(url)
+
+	outfile := outPathWithoutPartSuffix(partPath)
+	location := outfile
+	var size int64
+	if fi, statErr := os.Stat(outfile); statErr == nil {
+		size = fi.Size()
+	}
+
+	if config.Sink != "" && config.Sink != "local" {
+		progressChan <- ProgressMsg{ID: id, Status: StatusUploading, Title: title}
+		loc, sz, err := uploadCompletedFile(ctx, id, title, outfile, config, progressChan)
+		if err != nil {
+			return "", 0, err
+		}
+		location, size = loc, sz
+	}
+
+	return location, size, nil
+}
+
+// copyWithCheckpoint copies src into dst, invoking onChunk after each
+// successful write so the caller can persist a resume checkpoint.
+func copyWithCheckpoint(dst *os.File, src *countingReader, buf []byte, onChunk func(n int64)) error {
+	for {
+		n, readErr := src.Read(buf)
+		if n > 0 {
+			if _, writeErr := dst.Write(buf[:n]); writeErr != nil {
+				return writeErr
+			}
+			onChunk(int64(n))
+		}
+		if readErr != nil {
+			if errors.Is(readErr, io.EOF) {
+				return nil
+			}
+			return readErr
+		}
+	}
+}
+
+// transcodeFile runs ffmpeg against a complete, on-disk audio file.
+func transcodeFile(ctx context.Context, inPath string, config Config) error {
+	outfile := outPathWithoutPartSuffix(inPath)
+
+	cmd := exec.CommandContext(ctx, "ffmpeg",
+		"-y",
+		"-i", inPath,
+		"-vn",
+		"-c:a", ffmpegCodec(config.Format),
+		"-b:a", config.Quality+"k",
+		"-f", config.Format,
+		outfile,
+	)
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("ffmpeg: %w", err)
+	}
+
+	return nil
+}
+
+func outPathWithoutPartSuffix(partPath string) string {
+	const suffix = ".part"
+	if len(partPath) > len(suffix) && partPath[len(partPath)-len(suffix):] == suffix {
+		return partPath[:len(partPath)-len(suffix)]
+	}
+	return partPath
+}
@@ -0,0 +1,102 @@
+package downloader
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// stateFileName is the checkpoint file written under a download's output
+// directory so a killed process can resume where it left off.
+const stateFileName = ".gofetch-state.json"
+
+// saveInterval throttles how often Set flushes the checkpoint file to disk.
+// copyWithCheckpoint calls Set after every 32KB read, so without this a
+// multi-GB download would do a full JSON marshal + file write thousands of
+// times; Clear always flushes immediately regardless of this interval.
+const saveInterval = 2 * time.Second
+
+// Checkpoint records how much of a URL's download has already landed on
+// disk, so a restart can seek/Range-request past what's already there.
+type Checkpoint struct {
+	BytesWritten int64  `json:"bytes_written"`
+	PartPath     string `json:"part_path"`
+}
+
+// StateStore persists per-URL checkpoints to OutputDir/.gofetch-state.json.
+// A single StateStore must be shared (via Config.StateStore) across every
+// concurrent download in a run — one StateStore per goroutine would each
+// hold a private snapshot of the file and stomp each other's writes.
+type StateStore struct {
+	path string
+
+	mu       sync.Mutex
+	data     map[string]Checkpoint
+	lastSave time.Time
+}
+
+// OpenStateStore loads (or creates) the checkpoint file for outputDir.
+func OpenStateStore(outputDir string) (*StateStore, error) {
+	s := &StateStore{
+		path: filepath.Join(outputDir, stateFileName),
+		data: make(map[string]Checkpoint),
+	}
+
+	raw, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, err
+	}
+
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &s.data); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// Get returns the checkpoint recorded for url, if any.
+func (s *StateStore) Get(url string) (Checkpoint, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cp, ok := s.data[url]
+	return cp, ok
+}
+
+// Set records cp for url. The in-memory map is always updated; the flush to
+// disk is throttled to saveInterval so a fast-moving download doesn't do a
+// full file write on every chunk.
+func (s *StateStore) Set(url string, cp Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[url] = cp
+	if time.Since(s.lastSave) < saveInterval {
+		return nil
+	}
+	return s.save()
+}
+
+// Clear removes the checkpoint for url (called once a download completes)
+// and flushes the store to disk immediately.
+func (s *StateStore) Clear(url string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.data, url)
+	return s.save()
+}
+
+// save must be called with s.mu held.
+func (s *StateStore) save() error {
+	raw, err := json.MarshalIndent(s.data, "", "  ")
+	if err != nil {
+		return err
+	}
+	s.lastSave = time.Now()
+	return os.WriteFile(s.path, raw, 0644)
+}
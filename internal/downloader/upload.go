@@ -0,0 +1,37 @@
+package downloader
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// uploadCompletedFile hands a finished track on disk to config.Uploader,
+// reporting StatusUploading progress as bytes go out. Used by backends that
+// write the final file to disk themselves (e.g. yt-dlp) rather than
+// streaming ffmpeg's output directly into the uploader. Returns the
+// Uploader-reported location and the file's size.
+func uploadCompletedFile(ctx context.Context, id int, title, outPath string, config Config, progressChan chan<- ProgressMsg) (string, int64, error) {
+	f, err := os.Open(outPath)
+	if err != nil {
+		return "", 0, fmt.Errorf("open file to upload: %w", err)
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return "", 0, fmt.Errorf("stat file to upload: %w", err)
+	}
+
+	reader := &countingReader{r: f, total: fi.Size(), onProgress: func(pct float64) {
+		progressChan <- ProgressMsg{ID: id, Status: StatusUploading, Progress: pct, Title: title}
+	}}
+
+	location, err := config.Uploader.Upload(ctx, filepath.Base(outPath), reader, fi.Size())
+	if err != nil {
+		return "", 0, fmt.Errorf("upload: %w", err)
+	}
+
+	return location, fi.Size(), nil
+}
@@ -0,0 +1,309 @@
+// Package downloader provides functionality to download audio from YouTube videos
+// using either yt-dlp (YTDLPBackend) or a native YouTube client piped into ffmpeg
+// (NativeBackend). It handles progress reporting and audio extraction.
+package downloader
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ddbaque/gofetch-audio/internal/uploader"
+)
+
+type Status int
+
+const (
+	StatusPending Status = iota
+	StatusDownloading
+	StatusConverting
+	StatusAnalyzing
+	StatusUploading
+	StatusRetrying
+	StatusCompleted
+	StatusFailed
+)
+
+type ProgressMsg struct {
+	ID       int
+	Status   Status
+	Progress float64
+	Title    string
+	Attempt  int
+	// SpeedBps and ETASec are parsed from yt-dlp's [download] line when
+	// available; the native backend leaves them zero.
+	SpeedBps float64
+	ETASec   int
+	// OutputPath and Bytes are set on the terminal StatusCompleted message:
+	// OutputPath is the local path or Uploader-returned location of the
+	// finished track, and Bytes is its size.
+	OutputPath string
+	Bytes      int64
+	Error      error
+}
+
+type Config struct {
+	OutputDir string
+	Format    string
+	Quality   string
+
+	// MaxRetries is the number of additional attempts made after a
+	// failed attempt (e.g. HTTP 5xx, connection reset, ffmpeg SIGPIPE).
+	// Failures are not classified: every failed attempt is retried.
+	// Zero disables retrying.
+	MaxRetries int
+	// RetryBackoff is the base delay before a retry attempt; it doubles on
+	// each subsequent attempt (exponential backoff).
+	RetryBackoff time.Duration
+	// Resume re-attaches to a previously interrupted download instead of
+	// starting over, using the OutputDir/.gofetch-state.json checkpoint.
+	Resume bool
+	// StateStore backs Resume. It must be a single instance shared across
+	// every concurrent download in a run (opened once by the caller via
+	// OpenStateStore), since it is the sole writer of OutputDir's checkpoint
+	// file. Required when Resume is true.
+	StateStore *StateStore
+
+	// Peaks enables waveform peaks generation after audio extraction.
+	Peaks bool
+	// PeaksBins is the number of windows the waveform is downsampled into.
+	PeaksBins int
+
+	// Sink is the upload destination kind ("local" or "s3"); it mirrors
+	// Uploader so backends that already write the final file to disk
+	// (e.g. yt-dlp) can skip a redundant local upload.
+	Sink string
+	// Uploader receives the finished track. Never nil; defaults to a
+	// LocalUploader writing into OutputDir.
+	Uploader uploader.Uploader
+}
+
+// YTDLPBackend drives downloads by shelling out to yt-dlp and scraping its
+// progress output. This is the original, default backend.
+type YTDLPBackend struct{}
+
+func (b YTDLPBackend) Download(ctx context.Context, id int, url string, config Config, progressChan chan<- ProgressMsg) {
+	progressChan <- ProgressMsg{ID: id, Status: StatusDownloading, Progress: 0}
+
+	var title, outPath string
+	var err error
+
+	for attempt := 0; attempt <= config.MaxRetries; attempt++ {
+		if attempt > 0 {
+			progressChan <- ProgressMsg{ID: id, Status: StatusRetrying, Title: title, Attempt: attempt}
+			select {
+			case <-ctx.Done():
+				progressChan <- ProgressMsg{ID: id, Status: StatusFailed, Title: title, Error: ctx.Err()}
+				return
+			case <-time.After(retryDelay(config.RetryBackoff, attempt)):
+			}
+		}
+
+		title, outPath, err = b.runOnce(ctx, id, url, config, title, progressChan)
+		if err == nil {
+			break
+		}
+	}
+
+	if err != nil {
+		progressChan <- ProgressMsg{ID: id, Status: StatusFailed, Title: title, Error: err}
+		return
+	}
+
+	// The download itself already succeeded; peaks/upload are independent
+	// post-processing steps and must not re-run it on failure (and, for
+	// this backend, can't: a retried yt-dlp invocation would hit
+	// --no-overwrites and skip extraction entirely).
+	if config.Peaks {
+		progressChan <- ProgressMsg{ID: id, Status: StatusAnalyzing, Title: title}
+		if perr := generatePeaks(title, outPath, config); perr != nil {
+			progressChan <- ProgressMsg{ID: id, Status: StatusFailed, Title: title, Error: fmt.Errorf("generate peaks: %w", perr)}
+			return
+		}
+	}
+
+	location := outPath
+	var size int64
+	if fi, statErr := os.Stat(outPath); statErr == nil {
+		size = fi.Size()
+	}
+	if config.Sink != "" && config.Sink != "local" {
+		loc, sz, uerr := uploadCompletedFile(ctx, id, title, outPath, config, progressChan)
+		if uerr != nil {
+			progressChan <- ProgressMsg{ID: id, Status: StatusFailed, Title: title, Error: uerr}
+			return
+		}
+		location, size = loc, sz
+	}
+
+	progressChan <- ProgressMsg{ID: id, Status: StatusCompleted, Progress: 100, Title: title, OutputPath: location, Bytes: size}
+}
+
+// runOnce runs a single yt-dlp attempt and returns the resolved title and
+// output file path (if discovered) along with any failure.
+func (b YTDLPBackend) runOnce(ctx context.Context, id int, url string, config Config, title string, progressChan chan<- ProgressMsg) (string, string, error) {
+	args := []string{
+		"--extract-audio",
+		"--audio-format", config.Format,
+		"--audio-quality", config.Quality + "K",
+		"--output", filepath.Join(config.OutputDir, "%(title)s.%(ext)s"),
+		"--no-playlist",
+		"--no-overwrites",
+		"--restrict-filenames",
+		"--newline",
+		"--progress",
+		"--retries", fmt.Sprintf("%d", config.MaxRetries),
+		"--fragment-retries", fmt.Sprintf("%d", config.MaxRetries),
+	}
+	if config.Resume {
+		args = append(args, "--continue")
+	}
+	args = append(args, url)
+
+	cmd := exec.CommandContext(ctx, "yt-dlp", args...)
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return title, "", err
+	}
+
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return title, "", err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return title, "", err
+	}
+
+	downloadRe := regexp.MustCompile(`\[download\]\s+(\d+\.?\d*)%`)
+	destRe := regexp.MustCompile(`Destination:\s+.*/(.+)\.(webm|m4a|mp3|opus|wav)`)
+	extractRe := regexp.MustCompile(`\[ExtractAudio\]`)
+	speedRe := regexp.MustCompile(`at\s+(\d+\.?\d*)(KiB|MiB|GiB)/s`)
+	etaRe := regexp.MustCompile(`ETA\s+(\d+):(\d+)`)
+
+	var rawBase string
+
+	// Read stdout
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			line := scanner.Text()
+
+			if matches := destRe.FindStringSubmatch(line); len(matches) > 1 {
+				rawBase = matches[1]
+				title = strings.ReplaceAll(matches[1], "_", " ")
+				progressChan <- ProgressMsg{ID: id, Status: StatusDownloading, Title: title}
+			}
+
+			if matches := downloadRe.FindStringSubmatch(line); len(matches) > 1 {
+				var progress float64
+				fmt.Sscanf(matches[1], "%f", &progress)
+				progressChan <- ProgressMsg{
+					ID: id, Status: StatusDownloading, Progress: progress, Title: title,
+					SpeedBps: parseSpeed(speedRe.FindStringSubmatch(line)),
+					ETASec:   parseETA(etaRe.FindStringSubmatch(line)),
+				}
+			}
+
+			if extractRe.MatchString(line) {
+				progressChan <- ProgressMsg{ID: id, Status: StatusConverting, Progress: 100, Title: title}
+			}
+		}
+	}()
+
+	// Read stderr for errors
+	go func() {
+		scanner := bufio.NewScanner(stderr)
+		for scanner.Scan() {
+			line := scanner.Text()
+			// Check for title in stderr too
+			if matches := destRe.FindStringSubmatch(line); len(matches) > 1 {
+				rawBase = matches[1]
+				title = strings.ReplaceAll(matches[1], "_", " ")
+			}
+			if matches := downloadRe.FindStringSubmatch(line); len(matches) > 1 {
+				var progress float64
+				fmt.Sscanf(matches[1], "%f", &progress)
+				progressChan <- ProgressMsg{
+					ID: id, Status: StatusDownloading, Progress: progress, Title: title,
+					SpeedBps: parseSpeed(speedRe.FindStringSubmatch(line)),
+					ETASec:   parseETA(etaRe.FindStringSubmatch(line)),
+				}
+			}
+		}
+	}()
+
+	if err := cmd.Wait(); err != nil {
+		return title, "", fmt.Errorf("download failed")
+	}
+
+	var outPath string
+	if rawBase != "" {
+		outPath = filepath.Join(config.OutputDir, rawBase+"."+config.Format)
+	}
+
+	return title, outPath, nil
+}
+
+// speedUnitBytes maps yt-dlp's binary unit suffixes to a byte multiplier.
+var speedUnitBytes = map[string]float64{
+	"KiB": 1024,
+	"MiB": 1024 * 1024,
+	"GiB": 1024 * 1024 * 1024,
+}
+
+// parseSpeed converts a speedRe match (e.g. ["at 1.23MiB/s", "1.23", "MiB"])
+// into bytes per second. Returns 0 if matches is nil.
+func parseSpeed(matches []string) float64 {
+	if len(matches) < 3 {
+		return 0
+	}
+	var value float64
+	fmt.Sscanf(matches[1], "%f", &value)
+	return value * speedUnitBytes[matches[2]]
+}
+
+// parseETA converts an etaRe match (e.g. ["ETA 00:18", "00", "18"]) into
+// whole seconds. Returns 0 if matches is nil.
+func parseETA(matches []string) int {
+	if len(matches) < 3 {
+		return 0
+	}
+	var minutes, seconds int
+	fmt.Sscanf(matches[1], "%d", &minutes)
+	fmt.Sscanf(matches[2], "%d", &seconds)
+	return minutes*60 + seconds
+}
+
+// retryDelay returns the exponential backoff delay for a given retry
+// attempt (1-indexed): base, 2*base, 4*base, .... attempt 0 means no
+// retry has happened yet and always yields 0.
+func retryDelay(base time.Duration, attempt int) time.Duration {
+	if attempt < 1 {
+		return 0
+	}
+	return base << (attempt - 1)
+}
+
+// CheckDependencies verifies the external binaries the run will need are on
+// PATH. needsPlaylist should be true whenever playlist/channel expansion is
+// requested, since that always shells out to yt-dlp via YTDLPResolver
+// regardless of backend.
+func CheckDependencies(backend string, needsPlaylist bool) error {
+	if backend != "native" || needsPlaylist {
+		if _, err := exec.LookPath("yt-dlp"); err != nil {
+			return fmt.Errorf("yt-dlp not found. Install with: pipx install yt-dlp")
+		}
+	}
+	if _, err := exec.LookPath("ffmpeg"); err != nil {
+		return fmt.Errorf("ffmpeg not found. Install with your package manager")
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+// Package peaks computes a downsampled waveform peaks representation for an
+// audio file, suitable for rendering in external waveform viewers without a
+// second decode pass.
+package peaks
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+)
+
+const (
+	sampleRate = 48000
+	bits       = 16
+)
+
+// Peaks is the JSON sidecar format written next to a downloaded track.
+type Peaks struct {
+	Title       string  `json:"title"`
+	DurationSec float64 `json:"duration_sec"`
+	SampleRate  int     `json:"sample_rate"`
+	Bits        int     `json:"bits"`
+	Length      int     `json:"length"`
+	Data        []int16 `json:"data"`
+}
+
+// Compute decodes audioPath to mono 16-bit PCM via ffmpeg and reduces it to
+// numBins peaks, each the largest absolute sample value within its window.
+// The returned Peaks has Title left blank for the caller to fill in.
+func Compute(audioPath string, numBins int) (*Peaks, error) {
+	if numBins <= 0 {
+		return nil, fmt.Errorf("numBins must be positive, got %d", numBins)
+	}
+
+	cmd := exec.Command("ffmpeg",
+		"-i", audioPath,
+		"-ac", "1",
+		"-ar", fmt.Sprintf("%d", sampleRate),
+		"-f", "s16le",
+		"-acodec", "pcm_s16le",
+		"pipe:1",
+	)
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	samples, readErr := readSamples(stdout)
+
+	if err := cmd.Wait(); err != nil {
+		return nil, fmt.Errorf("ffmpeg: %w", err)
+	}
+	if readErr != nil {
+		return nil, fmt.Errorf("read pcm samples: %w", readErr)
+	}
+
+	return &Peaks{
+		DurationSec: float64(len(samples)) / float64(sampleRate),
+		SampleRate:  sampleRate,
+		Bits:        bits,
+		Length:      numBins,
+		Data:        binPeaks(samples, numBins),
+	}, nil
+}
+
+// WriteSidecar marshals p as `<name>.peaks.json` at path.
+func WriteSidecar(path string, p *Peaks) error {
+	raw, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, raw, 0644)
+}
+
+func readSamples(r io.Reader) ([]int16, error) {
+	var samples []int16
+	buf := make([]byte, 2)
+	for {
+		if _, err := io.ReadFull(r, buf); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				break
+			}
+			return nil, err
+		}
+		samples = append(samples, int16(binary.LittleEndian.Uint16(buf)))
+	}
+	return samples, nil
+}
+
+// binPeaks splits samples into numBins equal windows and records
+// max(abs(sample)) per window.
+func binPeaks(samples []int16, numBins int) []int16 {
+	if len(samples) == 0 {
+		return make([]int16, numBins)
+	}
+
+	peaks := make([]int16, numBins)
+	windowSize := float64(len(samples)) / float64(numBins)
+
+	for bin := 0; bin < numBins; bin++ {
+		start := int(float64(bin) * windowSize)
+		end := int(float64(bin+1) * windowSize)
+		if end > len(samples) {
+			end = len(samples)
+		}
+
+		// Accumulate in int32: abs(math.MinInt16) overflows int16.
+		var peak int32
+		for _, s := range samples[start:end] {
+			mag := int32(s)
+			if mag < 0 {
+				mag = -mag
+			}
+			if mag > peak {
+				peak = mag
+			}
+		}
+		if peak > math.MaxInt16 {
+			peak = math.MaxInt16
+		}
+		peaks[bin] = int16(peak)
+	}
+
+	return peaks
+}
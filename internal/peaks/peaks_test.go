@@ -0,0 +1,54 @@
+package peaks
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBinPeaks(t *testing.T) {
+	tests := []struct {
+		name    string
+		samples []int16
+		numBins int
+		want    []int16
+	}{
+		{
+			name:    "empty samples",
+			samples: nil,
+			numBins: 3,
+			want:    []int16{0, 0, 0},
+		},
+		{
+			name:    "one bin per sample",
+			samples: []int16{10, -20, 5},
+			numBins: 3,
+			want:    []int16{10, 20, 5},
+		},
+		{
+			name:    "full-scale negative sample does not overflow",
+			samples: []int16{math.MinInt16, 0},
+			numBins: 1,
+			want:    []int16{math.MaxInt16},
+		},
+		{
+			name:    "uneven window split",
+			samples: []int16{1, 2, 3, 4, 5},
+			numBins: 2,
+			want:    []int16{2, 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := binPeaks(tt.samples, tt.numBins)
+			if len(got) != len(tt.want) {
+				t.Fatalf("binPeaks(%v, %d) = %v, want %v", tt.samples, tt.numBins, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("binPeaks(%v, %d)[%d] = %d, want %d", tt.samples, tt.numBins, i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
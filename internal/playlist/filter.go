@@ -0,0 +1,116 @@
+package playlist
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Filter narrows a resolved playlist down to the entries the user asked
+// for. Zero values mean "no restriction" for that dimension.
+type Filter struct {
+	Start int // 1-based, inclusive; 0 means from the beginning
+	End   int // 1-based, inclusive; 0 means to the end
+
+	// Items is a comma-separated list of 1-based indices/ranges, e.g.
+	// "1,3,5-8". Empty means all indices pass.
+	Items string
+
+	// MatchTitle is a regular expression a title must match.
+	MatchTitle string
+
+	MinDuration float64 // seconds; 0 means no minimum
+	MaxDuration float64 // seconds; 0 means no maximum
+
+	// DateAfter is a YYYYMMDD string; entries uploaded on or before it
+	// are dropped. Empty means no restriction.
+	DateAfter string
+}
+
+// Apply returns the subset of entries passing every configured filter, in
+// their original order.
+func (f Filter) Apply(entries []Entry) ([]Entry, error) {
+	items, err := parseItems(f.Items)
+	if err != nil {
+		return nil, fmt.Errorf("parse -playlist-items: %w", err)
+	}
+
+	var titleRe *regexp.Regexp
+	if f.MatchTitle != "" {
+		titleRe, err = regexp.Compile(f.MatchTitle)
+		if err != nil {
+			return nil, fmt.Errorf("parse -match-title: %w", err)
+		}
+	}
+
+	var out []Entry
+	for i, e := range entries {
+		pos := i + 1
+
+		if f.Start > 0 && pos < f.Start {
+			continue
+		}
+		if f.End > 0 && pos > f.End {
+			continue
+		}
+		if items != nil && !items[pos] {
+			continue
+		}
+		if titleRe != nil && !titleRe.MatchString(e.Title) {
+			continue
+		}
+		if f.MinDuration > 0 && e.Duration < f.MinDuration {
+			continue
+		}
+		if f.MaxDuration > 0 && e.Duration > f.MaxDuration {
+			continue
+		}
+		if f.DateAfter != "" && e.UploadDate != "" && e.UploadDate <= f.DateAfter {
+			continue
+		}
+
+		out = append(out, e)
+	}
+
+	return out, nil
+}
+
+// parseItems parses a spec like "1,3,5-8" into a set of 1-based indices.
+// A nil, nil return means no -playlist-items restriction was given.
+func parseItems(spec string) (map[int]bool, error) {
+	if spec == "" {
+		return nil, nil
+	}
+
+	set := make(map[int]bool)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		if start, end, ok := strings.Cut(part, "-"); ok {
+			lo, err := strconv.Atoi(start)
+			if err != nil {
+				return nil, fmt.Errorf("invalid range %q: %w", part, err)
+			}
+			hi, err := strconv.Atoi(end)
+			if err != nil {
+				return nil, fmt.Errorf("invalid range %q: %w", part, err)
+			}
+			for i := lo; i <= hi; i++ {
+				set[i] = true
+			}
+			continue
+		}
+
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid item %q: %w", part, err)
+		}
+		set[n] = true
+	}
+
+	return set, nil
+}
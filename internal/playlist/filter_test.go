@@ -0,0 +1,123 @@
+package playlist
+
+import "testing"
+
+func TestParseItems(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    map[int]bool
+		wantErr bool
+	}{
+		{name: "empty spec means no restriction", spec: "", want: nil},
+		{name: "single indices", spec: "1,3", want: map[int]bool{1: true, 3: true}},
+		{name: "range", spec: "5-8", want: map[int]bool{5: true, 6: true, 7: true, 8: true}},
+		{name: "mixed with whitespace", spec: "1, 3, 5-8", want: map[int]bool{1: true, 3: true, 5: true, 6: true, 7: true, 8: true}},
+		{name: "invalid item", spec: "x", wantErr: true},
+		{name: "invalid range", spec: "1-x", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseItems(tt.spec)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseItems(%q) = nil error, want error", tt.spec)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseItems(%q) unexpected error: %v", tt.spec, err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseItems(%q) = %v, want %v", tt.spec, got, tt.want)
+			}
+			for k := range tt.want {
+				if !got[k] {
+					t.Errorf("parseItems(%q)[%d] = false, want true", tt.spec, k)
+				}
+			}
+		})
+	}
+}
+
+func TestFilterApply(t *testing.T) {
+	entries := []Entry{
+		{Title: "Intro", Duration: 30, UploadDate: "20200101"},
+		{Title: "Track One", Duration: 200, UploadDate: "20210601"},
+		{Title: "Track Two", Duration: 400, UploadDate: "20220301"},
+		{Title: "Outro", Duration: 20, UploadDate: "20230101"},
+	}
+
+	tests := []struct {
+		name       string
+		filter     Filter
+		wantTitles []string
+		wantErr    bool
+	}{
+		{
+			name:       "no filter passes everything",
+			filter:     Filter{},
+			wantTitles: []string{"Intro", "Track One", "Track Two", "Outro"},
+		},
+		{
+			name:       "start and end range",
+			filter:     Filter{Start: 2, End: 3},
+			wantTitles: []string{"Track One", "Track Two"},
+		},
+		{
+			name:       "items restricts to explicit positions",
+			filter:     Filter{Items: "1,4"},
+			wantTitles: []string{"Intro", "Outro"},
+		},
+		{
+			name:       "match title regex",
+			filter:     Filter{MatchTitle: "^Track"},
+			wantTitles: []string{"Track One", "Track Two"},
+		},
+		{
+			name:       "duration bounds",
+			filter:     Filter{MinDuration: 100, MaxDuration: 300},
+			wantTitles: []string{"Track One"},
+		},
+		{
+			name:       "date after excludes on-or-before",
+			filter:     Filter{DateAfter: "20210601"},
+			wantTitles: []string{"Track Two", "Outro"},
+		},
+		{
+			name:    "invalid match-title regex errors",
+			filter:  Filter{MatchTitle: "("},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.filter.Apply(entries)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Apply() = nil error, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Apply() unexpected error: %v", err)
+			}
+
+			var gotTitles []string
+			for _, e := range got {
+				gotTitles = append(gotTitles, e.Title)
+			}
+			if len(gotTitles) != len(tt.wantTitles) {
+				t.Fatalf("Apply() titles = %v, want %v", gotTitles, tt.wantTitles)
+			}
+			for i, title := range tt.wantTitles {
+				if gotTitles[i] != title {
+					t.Errorf("Apply() titles = %v, want %v", gotTitles, tt.wantTitles)
+					break
+				}
+			}
+		})
+	}
+}
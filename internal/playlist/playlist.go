@@ -0,0 +1,73 @@
+// Package playlist resolves a YouTube playlist or channel URL into its
+// individual video URLs and applies range/regex/duration/date filters
+// before they're handed to the downloader.
+package playlist
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+)
+
+// Entry is one playlist item as resolved by a Resolver.
+type Entry struct {
+	ID         string
+	Title      string
+	Uploader   string
+	Duration   float64
+	UploadDate string // YYYYMMDD
+	URL        string
+}
+
+// Resolver expands a playlist or channel URL into its individual entries.
+type Resolver interface {
+	Resolve(ctx context.Context, url string) ([]Entry, error)
+}
+
+// YTDLPResolver resolves a playlist via `yt-dlp --flat-playlist
+// --dump-single-json`, which returns metadata for every entry without
+// downloading them.
+type YTDLPResolver struct{}
+
+func (YTDLPResolver) Resolve(ctx context.Context, url string) ([]Entry, error) {
+	cmd := exec.CommandContext(ctx, "yt-dlp", "--flat-playlist", "--dump-single-json", url)
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("resolve playlist: %w", err)
+	}
+
+	var result struct {
+		Entries []struct {
+			ID         string  `json:"id"`
+			Title      string  `json:"title"`
+			Uploader   string  `json:"uploader"`
+			Duration   float64 `json:"duration"`
+			UploadDate string  `json:"upload_date"`
+			URL        string  `json:"url"`
+			WebpageURL string  `json:"webpage_url"`
+		} `json:"entries"`
+	}
+	if err := json.Unmarshal(out, &result); err != nil {
+		return nil, fmt.Errorf("parse playlist json: %w", err)
+	}
+
+	entries := make([]Entry, len(result.Entries))
+	for i, e := range result.Entries {
+		videoURL := e.WebpageURL
+		if videoURL == "" {
+			videoURL = e.URL
+		}
+		entries[i] = Entry{
+			ID:         e.ID,
+			Title:      e.Title,
+			Uploader:   e.Uploader,
+			Duration:   e.Duration,
+			UploadDate: e.UploadDate,
+			URL:        videoURL,
+		}
+	}
+
+	return entries, nil
+}
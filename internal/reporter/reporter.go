@@ -0,0 +1,143 @@
+// Package reporter runs downloads without a terminal UI, emitting either
+// newline-delimited JSON or plain text progress lines to an io.Writer. It is
+// the -output-format {json,plain} counterpart to the tui package.
+package reporter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ddbaque/gofetch-audio/internal/downloader"
+)
+
+// Format selects how events are rendered.
+type Format string
+
+const (
+	FormatJSON  Format = "json"
+	FormatPlain Format = "plain"
+)
+
+// Event is one newline-delimited JSON record describing a ProgressMsg
+// transition for a single track.
+type Event struct {
+	ID         int     `json:"id"`
+	URL        string  `json:"url"`
+	Title      string  `json:"title,omitempty"`
+	Status     string  `json:"status"`
+	Progress   float64 `json:"progress"`
+	ETASec     int     `json:"eta_sec,omitempty"`
+	SpeedBps   float64 `json:"speed_bps,omitempty"`
+	Timestamp  string  `json:"ts"`
+	OutputPath string  `json:"output_path,omitempty"`
+	Bytes      int64   `json:"bytes,omitempty"`
+	DurationMs int64   `json:"duration_ms,omitempty"`
+	Error      string  `json:"error,omitempty"`
+}
+
+var statusNames = map[downloader.Status]string{
+	downloader.StatusPending:     "pending",
+	downloader.StatusDownloading: "downloading",
+	downloader.StatusConverting:  "converting",
+	downloader.StatusAnalyzing:   "analyzing",
+	downloader.StatusUploading:   "uploading",
+	downloader.StatusRetrying:    "retrying",
+	downloader.StatusCompleted:   "completed",
+	downloader.StatusFailed:      "failed",
+}
+
+// Reporter drives the same parallel download machinery as the TUI, but
+// writes each ProgressMsg transition to Out instead of rendering a screen.
+type Reporter struct {
+	URLs     []string
+	Config   downloader.Config
+	Backend  downloader.Backend
+	Parallel int
+	Format   Format
+	Out      io.Writer
+}
+
+// Run downloads every URL, blocking until all complete or ctx is cancelled.
+func (r *Reporter) Run(ctx context.Context) error {
+	progressCh := make(chan downloader.ProgressMsg, 100)
+	started := make([]time.Time, len(r.URLs))
+
+	var active, next int
+	launch := func(i int) {
+		started[i] = time.Now()
+		active++
+		go r.Backend.Download(ctx, i, r.URLs[i], r.Config, progressCh)
+	}
+
+	for next < len(r.URLs) && active < r.Parallel {
+		launch(next)
+		next++
+	}
+
+	remaining := len(r.URLs)
+	for remaining > 0 {
+		msg := <-progressCh
+		r.emit(msg, started)
+
+		if msg.Status == downloader.StatusCompleted || msg.Status == downloader.StatusFailed {
+			active--
+			remaining--
+			if next < len(r.URLs) {
+				launch(next)
+				next++
+			}
+		}
+	}
+
+	return nil
+}
+
+func (r *Reporter) emit(msg downloader.ProgressMsg, started []time.Time) {
+	event := Event{
+		ID:        msg.ID,
+		URL:       r.URLs[msg.ID],
+		Title:     msg.Title,
+		Status:    statusNames[msg.Status],
+		Progress:  msg.Progress,
+		ETASec:    msg.ETASec,
+		SpeedBps:  msg.SpeedBps,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	if msg.Status == downloader.StatusCompleted || msg.Status == downloader.StatusFailed {
+		event.DurationMs = time.Since(started[msg.ID]).Milliseconds()
+	}
+	if msg.Status == downloader.StatusCompleted {
+		event.OutputPath = msg.OutputPath
+		event.Bytes = msg.Bytes
+	}
+	if msg.Error != nil {
+		event.Error = msg.Error.Error()
+	}
+
+	switch r.Format {
+	case FormatPlain:
+		r.writePlain(event)
+	default:
+		r.writeJSON(event)
+	}
+}
+
+func (r *Reporter) writeJSON(event Event) {
+	raw, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	fmt.Fprintln(r.Out, string(raw))
+}
+
+func (r *Reporter) writePlain(event Event) {
+	if event.Error != "" {
+		fmt.Fprintf(r.Out, "[%d] %s %s (%s)\n", event.ID, event.Status, event.Title, event.Error)
+		return
+	}
+	fmt.Fprintf(r.Out, "[%d] %s %.1f%% %s\n", event.ID, event.Status, event.Progress, event.Title)
+}
@@ -4,6 +4,7 @@
 package tui
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -12,6 +13,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/ddbaque/gofetch-audio/internal/downloader"
+	"github.com/ddbaque/gofetch-audio/internal/playlist"
 )
 
 var (
@@ -39,9 +41,11 @@ var (
 	mutedStyle   = lipgloss.NewStyle().Foreground(mutedColor)
 	warnStyle    = lipgloss.NewStyle().Foreground(warningColor)
 
-	checkMark = successStyle.Render("✓")
-	crossMark = errorStyle.Render("✗")
-	pending   = mutedStyle.Render("○")
+	checkMark   = successStyle.Render("✓")
+	crossMark   = errorStyle.Render("✗")
+	pending     = mutedStyle.Render("○")
+	retryMark   = warnStyle.Render("↻")
+	analyzeMark = mutedStyle.Render("◈")
 )
 
 type Item struct {
@@ -49,12 +53,14 @@ type Item struct {
 	Title    string
 	Status   downloader.Status
 	Progress float64
+	Attempt  int
 	Error    error
 }
 
 type Model struct {
 	items       []Item
 	config      downloader.Config
+	backend     downloader.Backend
 	parallel    int
 	spinner     spinner.Model
 	progress    progress.Model
@@ -65,9 +71,17 @@ type Model struct {
 	width       int
 	quitting    bool
 	done        bool
+
+	// Playlist resolution. When resolver is non-nil, Init shows a
+	// "resolving playlist…" phase before the item list is populated.
+	resolving    bool
+	resolver     playlist.Resolver
+	playlistURLs []string
+	filter       playlist.Filter
+	resolveErr   error
 }
 
-func NewModel(urls []string, config downloader.Config, parallel int) Model {
+func NewModel(urls []string, config downloader.Config, backend downloader.Backend, parallel int) Model {
 	s := spinner.New()
 	s.Spinner = spinner.Dot
 	s.Style = lipgloss.NewStyle().Foreground(primaryColor)
@@ -86,6 +100,7 @@ func NewModel(urls []string, config downloader.Config, parallel int) Model {
 	return Model{
 		items:      items,
 		config:     config,
+		backend:    backend,
 		parallel:   parallel,
 		spinner:    s,
 		progress:   p,
@@ -94,7 +109,22 @@ func NewModel(urls []string, config downloader.Config, parallel int) Model {
 	}
 }
 
+// NewPlaylistModel is like NewModel, but urls are playlist/channel URLs
+// that get resolved into individual video URLs (and filtered) during Init,
+// instead of being downloaded directly.
+func NewPlaylistModel(urls []string, config downloader.Config, backend downloader.Backend, parallel int, resolver playlist.Resolver, filter playlist.Filter) Model {
+	m := NewModel(nil, config, backend, parallel)
+	m.resolving = true
+	m.resolver = resolver
+	m.playlistURLs = urls
+	m.filter = filter
+	return m
+}
+
 func (m Model) Init() tea.Cmd {
+	if m.resolving {
+		return tea.Batch(m.spinner.Tick, m.resolvePlaylist())
+	}
 	return tea.Batch(
 		m.spinner.Tick,
 		m.startDownloads(),
@@ -102,6 +132,38 @@ func (m Model) Init() tea.Cmd {
 	)
 }
 
+type playlistResolvedMsg struct {
+	items []Item
+	err   error
+}
+
+// resolvePlaylist expands every seed playlist/channel URL into its
+// individual entries, applies the configured filter, and reports the
+// resulting item list back to Update.
+func (m Model) resolvePlaylist() tea.Cmd {
+	return func() tea.Msg {
+		var entries []playlist.Entry
+		for _, url := range m.playlistURLs {
+			resolved, err := m.resolver.Resolve(context.Background(), url)
+			if err != nil {
+				return playlistResolvedMsg{err: err}
+			}
+			entries = append(entries, resolved...)
+		}
+
+		filtered, err := m.filter.Apply(entries)
+		if err != nil {
+			return playlistResolvedMsg{err: err}
+		}
+
+		items := make([]Item, len(filtered))
+		for i, e := range filtered {
+			items[i] = Item{URL: e.URL, Title: e.Title, Status: downloader.StatusPending}
+		}
+		return playlistResolvedMsg{items: items}
+	}
+}
+
 func (m *Model) startDownloads() tea.Cmd {
 	return func() tea.Msg {
 		started := 0
@@ -110,7 +172,7 @@ func (m *Model) startDownloads() tea.Cmd {
 				break
 			}
 			if m.items[i].Status == downloader.StatusPending {
-				go downloader.Download(i, m.items[i].URL, m.config, m.progressCh)
+				go m.backend.Download(context.Background(), i, m.items[i].URL, m.config, m.progressCh)
 				started++
 				m.activeCount++
 			}
@@ -145,6 +207,20 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case downloader.ProgressMsg:
 		return m.handleProgress(msg)
+
+	case playlistResolvedMsg:
+		m.resolving = false
+		if msg.err != nil {
+			m.resolveErr = msg.err
+			m.quitting = true
+			return m, tea.Quit
+		}
+		m.items = msg.items
+		if len(m.items) == 0 {
+			m.done = true
+			return m, tea.Quit
+		}
+		return m, tea.Batch(m.startDownloads(), m.waitForProgress())
 	}
 
 	return m, nil
@@ -161,6 +237,9 @@ func (m Model) handleProgress(msg downloader.ProgressMsg) (tea.Model, tea.Cmd) {
 	if msg.Title != "" {
 		item.Title = msg.Title
 	}
+	if msg.Status == downloader.StatusRetrying {
+		item.Attempt = msg.Attempt
+	}
 	if msg.Error != nil {
 		item.Error = msg.Error
 	}
@@ -180,7 +259,7 @@ func (m Model) handleProgress(msg downloader.ProgressMsg) (tea.Model, tea.Cmd) {
 		// Start next download if any pending
 		for i := range m.items {
 			if m.items[i].Status == downloader.StatusPending && m.activeCount < m.parallel {
-				go downloader.Download(i, m.items[i].URL, m.config, m.progressCh)
+				go m.backend.Download(context.Background(), i, m.items[i].URL, m.config, m.progressCh)
 				m.activeCount++
 				break
 			}
@@ -198,10 +277,18 @@ func (m Model) handleProgress(msg downloader.ProgressMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m Model) View() string {
+	if m.resolveErr != nil {
+		return errorStyle.Render(fmt.Sprintf("\n  Error resolving playlist: %v\n\n", m.resolveErr))
+	}
+
 	if m.quitting {
 		return "\n  Cancelled.\n\n"
 	}
 
+	if m.resolving {
+		return fmt.Sprintf("\n  %s %s\n\n", m.spinner.View(), mutedStyle.Render("Resolving playlist..."))
+	}
+
 	var b strings.Builder
 
 	// Header
@@ -284,6 +371,20 @@ func (m Model) renderItem(item Item) string {
 		status = warnStyle.Render("⚙")
 		line = fmt.Sprintf("  %s %s %s\n", status, title, warnStyle.Render("converting..."))
 
+	case downloader.StatusRetrying:
+		status = retryMark
+		line = fmt.Sprintf("  %s %s %s\n", status, title, warnStyle.Render(fmt.Sprintf("retrying (attempt %d)...", item.Attempt)))
+
+	case downloader.StatusAnalyzing:
+		status = analyzeMark
+		line = fmt.Sprintf("  %s %s %s\n", status, title, mutedStyle.Render("analyzing waveform..."))
+
+	case downloader.StatusUploading:
+		status = m.spinner.View()
+		prog := m.progress.ViewAs(item.Progress / 100)
+		pct := fmt.Sprintf("%3.0f%%", item.Progress)
+		line = fmt.Sprintf("  %s %s %s %s %s\n", status, title, prog, pct, mutedStyle.Render("uploading"))
+
 	case downloader.StatusCompleted:
 		status = checkMark
 		line = fmt.Sprintf("  %s %s\n", status, successStyle.Render(title))
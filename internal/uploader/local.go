@@ -0,0 +1,30 @@
+package uploader
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// LocalUploader "uploads" by writing to a file under Dir. It is the default
+// sink and keeps previous on-disk behavior.
+type LocalUploader struct {
+	Dir string
+}
+
+func (u LocalUploader) Upload(ctx context.Context, key string, r io.Reader, size int64) (string, error) {
+	path := filepath.Join(u.Dir, key)
+
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, r); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
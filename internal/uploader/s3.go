@@ -0,0 +1,157 @@
+package uploader
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// partSize is the chunk size used for each UploadPart call. S3 requires at
+// least 5MB for all but the final part.
+const partSize = 8 * 1024 * 1024
+
+// maxConcurrentParts bounds how many parts are in flight at once.
+const maxConcurrentParts = 4
+
+// S3Config identifies the bucket/prefix/region an S3Uploader writes to.
+type S3Config struct {
+	Bucket string
+	Prefix string
+	Region string
+}
+
+// S3Uploader streams tracks into S3 via multipart upload: CreateMultipartUpload,
+// a bounded pool of concurrent UploadPart calls, then CompleteMultipartUpload.
+// The upload is aborted if the context is cancelled or any part fails.
+type S3Uploader struct {
+	client *s3.Client
+	cfg    S3Config
+}
+
+// NewS3Uploader loads AWS credentials from the default chain and constructs
+// an S3Uploader for cfg.
+func NewS3Uploader(ctx context.Context, cfg S3Config) (*S3Uploader, error) {
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(cfg.Region))
+	if err != nil {
+		return nil, fmt.Errorf("load aws config: %w", err)
+	}
+	return &S3Uploader{client: s3.NewFromConfig(awsCfg), cfg: cfg}, nil
+}
+
+func (u *S3Uploader) Upload(ctx context.Context, key string, r io.Reader, size int64) (string, error) {
+	fullKey := path.Join(u.cfg.Prefix, key)
+
+	create, err := u.client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket: aws.String(u.cfg.Bucket),
+		Key:    aws.String(fullKey),
+	})
+	if err != nil {
+		return "", fmt.Errorf("create multipart upload: %w", err)
+	}
+	uploadID := create.UploadId
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		sem      = make(chan struct{}, maxConcurrentParts)
+		mu       sync.Mutex
+		parts    []types.CompletedPart
+		firstErr error
+	)
+
+	fail := func(err error) {
+		mu.Lock()
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+		mu.Unlock()
+	}
+
+	partNum := int32(1)
+	for {
+		buf := make([]byte, partSize)
+		n, readErr := io.ReadFull(r, buf)
+		if n > 0 {
+			sem <- struct{}{}
+			wg.Add(1)
+			go func(num int32, body []byte) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				out, err := u.client.UploadPart(ctx, &s3.UploadPartInput{
+					Bucket:     aws.String(u.cfg.Bucket),
+					Key:        aws.String(fullKey),
+					PartNumber: aws.Int32(num),
+					UploadId:   uploadID,
+					Body:       bytes.NewReader(body),
+				})
+				if err != nil {
+					fail(fmt.Errorf("upload part %d: %w", num, err))
+					return
+				}
+
+				mu.Lock()
+				parts = append(parts, types.CompletedPart{ETag: out.ETag, PartNumber: aws.Int32(num)})
+				mu.Unlock()
+			}(partNum, buf[:n])
+			partNum++
+		}
+		if readErr != nil {
+			if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+				break
+			}
+			fail(fmt.Errorf("read part: %w", readErr))
+			break
+		}
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		u.abort(context.Background(), fullKey, uploadID)
+		return "", firstErr
+	}
+
+	sortParts(parts)
+
+	if _, err := u.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(u.cfg.Bucket),
+		Key:             aws.String(fullKey),
+		UploadId:        uploadID,
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: parts},
+	}); err != nil {
+		u.abort(context.Background(), fullKey, uploadID)
+		return "", fmt.Errorf("complete multipart upload: %w", err)
+	}
+
+	return fmt.Sprintf("s3://%s/%s", u.cfg.Bucket, fullKey), nil
+}
+
+func (u *S3Uploader) abort(ctx context.Context, key string, uploadID *string) {
+	u.client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(u.cfg.Bucket),
+		Key:      aws.String(key),
+		UploadId: uploadID,
+	})
+}
+
+// sortParts orders completed parts by PartNumber; S3 requires them
+// ascending in CompleteMultipartUpload.
+func sortParts(parts []types.CompletedPart) {
+	for i := 1; i < len(parts); i++ {
+		for j := i; j > 0 && *parts[j-1].PartNumber > *parts[j].PartNumber; j-- {
+			parts[j-1], parts[j] = parts[j], parts[j-1]
+		}
+	}
+}
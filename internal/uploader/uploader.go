@@ -0,0 +1,43 @@
+// Package uploader streams finished audio tracks to a storage destination
+// instead of (or in addition to) leaving them on local disk.
+package uploader
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// Uploader streams r (size bytes, or 0 when not known) to a destination under key
+// and returns a URL (or local path) identifying where it landed.
+type Uploader interface {
+	Upload(ctx context.Context, key string, r io.Reader, size int64) (url string, err error)
+}
+
+// Config selects and configures a sink for the -sink CLI flag.
+type Config struct {
+	Sink     string // "local" or "s3"
+	Dir      string // local sink output directory
+	S3Bucket string
+	S3Prefix string
+	S3Region string
+}
+
+// New resolves a Config into an Uploader.
+func New(ctx context.Context, cfg Config) (Uploader, error) {
+	switch cfg.Sink {
+	case "", "local":
+		return LocalUploader{Dir: cfg.Dir}, nil
+	case "s3":
+		if cfg.S3Bucket == "" {
+			return nil, fmt.Errorf("-s3-bucket is required for -sink s3")
+		}
+		return NewS3Uploader(ctx, S3Config{
+			Bucket: cfg.S3Bucket,
+			Prefix: cfg.S3Prefix,
+			Region: cfg.S3Region,
+		})
+	default:
+		return nil, fmt.Errorf("unknown sink %q (want local or s3)", cfg.Sink)
+	}
+}
@@ -4,23 +4,60 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/ddbaque/gofetch-audio/internal/downloader"
+	"github.com/ddbaque/gofetch-audio/internal/playlist"
+	"github.com/ddbaque/gofetch-audio/internal/reporter"
 	"github.com/ddbaque/gofetch-audio/internal/tui"
+	"github.com/ddbaque/gofetch-audio/internal/uploader"
 )
 
 type Config struct {
-	URLs      []string
-	OutputDir string
-	Format    string
-	Quality   string
-	Parallel  int
+	URLs         []string
+	OutputDir    string
+	Format       string
+	Quality      string
+	Parallel     int
+	Backend      string
+	MaxRetries   int
+	RetryBackoff time.Duration
+	Resume       bool
+	Peaks        bool
+	PeaksBins    int
+	Sink         string
+	S3Bucket     string
+	S3Prefix     string
+	S3Region     string
+	OutputFormat string
+
+	Playlist      bool
+	PlaylistStart int
+	PlaylistEnd   int
+	PlaylistItems string
+	MatchTitle    string
+	MinDuration   float64
+	MaxDuration   float64
+	DateAfter     string
+}
+
+func (c Config) playlistFilter() playlist.Filter {
+	return playlist.Filter{
+		Start:       c.PlaylistStart,
+		End:         c.PlaylistEnd,
+		Items:       c.PlaylistItems,
+		MatchTitle:  c.MatchTitle,
+		MinDuration: c.MinDuration,
+		MaxDuration: c.MaxDuration,
+		DateAfter:   c.DateAfter,
+	}
 }
 
 func main() {
@@ -32,7 +69,13 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err := downloader.CheckDependencies(); err != nil {
+	if err := downloader.CheckDependencies(config.Backend, config.Playlist); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	backend, err := downloader.NewBackend(config.Backend)
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
@@ -42,13 +85,76 @@ func main() {
 		os.Exit(1)
 	}
 
+	sink, err := uploader.New(context.Background(), uploader.Config{
+		Sink:     config.Sink,
+		Dir:      config.OutputDir,
+		S3Bucket: config.S3Bucket,
+		S3Prefix: config.S3Prefix,
+		S3Region: config.S3Region,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	var stateStore *downloader.StateStore
+	if config.Resume {
+		// Shared across every concurrent download in this run; each one
+		// opening its own StateStore would stomp the others' checkpoints.
+		stateStore, err = downloader.OpenStateStore(config.OutputDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	dlConfig := downloader.Config{
-		OutputDir: config.OutputDir,
-		Format:    config.Format,
-		Quality:   config.Quality,
+		OutputDir:    config.OutputDir,
+		Format:       config.Format,
+		Quality:      config.Quality,
+		MaxRetries:   config.MaxRetries,
+		RetryBackoff: config.RetryBackoff,
+		Resume:       config.Resume,
+		StateStore:   stateStore,
+		Peaks:        config.Peaks,
+		PeaksBins:    config.PeaksBins,
+		Sink:         config.Sink,
+		Uploader:     sink,
+	}
+
+	if config.OutputFormat == "json" || config.OutputFormat == "plain" {
+		urls := config.URLs
+		if config.Playlist {
+			fmt.Fprintln(os.Stderr, "Resolving playlist...")
+			resolved, err := resolvePlaylistURLs(config)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			urls = resolved
+		}
+
+		r := &reporter.Reporter{
+			URLs:     urls,
+			Config:   dlConfig,
+			Backend:  backend,
+			Parallel: config.Parallel,
+			Format:   reporter.Format(config.OutputFormat),
+			Out:      os.Stdout,
+		}
+		if err := r.Run(context.Background()); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
 	}
 
-	model := tui.NewModel(config.URLs, dlConfig, config.Parallel)
+	var model tui.Model
+	if config.Playlist {
+		model = tui.NewPlaylistModel(config.URLs, dlConfig, backend, config.Parallel, playlist.YTDLPResolver{}, config.playlistFilter())
+	} else {
+		model = tui.NewModel(config.URLs, dlConfig, backend, config.Parallel)
+	}
 	p := tea.NewProgram(model)
 
 	if _, err := p.Run(); err != nil {
@@ -57,10 +163,38 @@ func main() {
 	}
 }
 
+// resolvePlaylistURLs expands every seed playlist/channel URL into its
+// individual video URLs and applies the configured filter, for use by the
+// non-interactive (-output-format json/plain) reporter path.
+func resolvePlaylistURLs(config Config) ([]string, error) {
+	resolver := playlist.YTDLPResolver{}
+
+	var entries []playlist.Entry
+	for _, url := range config.URLs {
+		resolved, err := resolver.Resolve(context.Background(), url)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, resolved...)
+	}
+
+	filtered, err := config.playlistFilter().Apply(entries)
+	if err != nil {
+		return nil, err
+	}
+
+	urls := make([]string, len(filtered))
+	for i, e := range filtered {
+		urls[i] = e.URL
+	}
+	return urls, nil
+}
+
 func parseFlags() Config {
 	var config Config
 	var urlList string
 	var urlFile string
+	var jsonShorthand bool
 
 	flag.StringVar(&urlList, "urls", "", "Comma-separated list of YouTube URLs")
 	flag.StringVar(&urlFile, "file", "", "File containing URLs (one per line)")
@@ -68,6 +202,26 @@ func parseFlags() Config {
 	flag.StringVar(&config.Format, "format", "mp3", "Audio format (mp3, m4a, opus, wav)")
 	flag.StringVar(&config.Quality, "quality", "192", "Audio quality in kbps (128, 192, 256, 320)")
 	flag.IntVar(&config.Parallel, "parallel", 3, "Number of parallel downloads")
+	flag.StringVar(&config.Backend, "backend", "ytdlp", "Download backend (ytdlp, native)")
+	flag.IntVar(&config.MaxRetries, "retries", 3, "Number of retry attempts after a failed download attempt")
+	flag.DurationVar(&config.RetryBackoff, "retry-backoff", 2*time.Second, "Delay before the first retry attempt; doubles on each further attempt")
+	flag.BoolVar(&config.Resume, "resume", false, "Resume interrupted downloads using checkpoints in OutputDir/.gofetch-state.json")
+	flag.BoolVar(&config.Peaks, "peaks", false, "Generate a waveform peaks sidecar (<name>.peaks.json) for each track kept on local disk (not done by -backend native with a non-local -sink unless -resume is set)")
+	flag.IntVar(&config.PeaksBins, "peaks-bins", 800, "Number of waveform peak bins to generate")
+	flag.StringVar(&config.Sink, "sink", "local", "Upload destination for finished tracks (local, s3)")
+	flag.StringVar(&config.S3Bucket, "s3-bucket", "", "S3 bucket to upload to (required for -sink s3)")
+	flag.StringVar(&config.S3Prefix, "s3-prefix", "", "Key prefix for S3 uploads")
+	flag.StringVar(&config.S3Region, "s3-region", "", "AWS region for S3 uploads")
+	flag.StringVar(&config.OutputFormat, "output-format", "tui", "Progress output format (tui, json, plain)")
+	flag.BoolVar(&jsonShorthand, "json", false, "Shorthand for -output-format json")
+	flag.BoolVar(&config.Playlist, "playlist", false, "Treat URLs as playlists/channels and expand them before downloading")
+	flag.IntVar(&config.PlaylistStart, "playlist-start", 0, "First playlist entry to download (1-based, inclusive)")
+	flag.IntVar(&config.PlaylistEnd, "playlist-end", 0, "Last playlist entry to download (1-based, inclusive)")
+	flag.StringVar(&config.PlaylistItems, "playlist-items", "", "Comma-separated playlist indices/ranges to download, e.g. \"1,3,5-8\"")
+	flag.StringVar(&config.MatchTitle, "match-title", "", "Only download entries whose title matches this regular expression")
+	flag.Float64Var(&config.MinDuration, "min-duration", 0, "Skip entries shorter than this many seconds")
+	flag.Float64Var(&config.MaxDuration, "max-duration", 0, "Skip entries longer than this many seconds")
+	flag.StringVar(&config.DateAfter, "date-after", "", "Only download entries uploaded after this date (YYYYMMDD); entries with no known upload date are kept")
 
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "gofetch-audio - Download audio from YouTube videos\n\n")
@@ -85,6 +239,10 @@ func parseFlags() Config {
 
 	flag.Parse()
 
+	if jsonShorthand {
+		config.OutputFormat = "json"
+	}
+
 	// Read URLs from file if specified
 	if urlFile != "" {
 		fileURLs, err := readURLsFromFile(urlFile)